@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/trycog/cog-cli/pkg/trace"
+)
+
+func init() {
+	register(command{
+		name:  "attach",
+		usage: "sample a running process and report mutator utilization",
+		run:   runAttach,
+	})
+}
+
+// runAttach reports OS-level CPU-time utilization, not a trace-exact
+// mutator utilization function: there is deliberately no flag here to
+// include/exclude STW, mark-assist, background-mark or sweep spans,
+// since CPU-time sampling can't distinguish GC phases in the first
+// place — see the scope note on pkg/trace's package doc.
+func runAttach(args []string) error {
+	fs := flag.NewFlagSet("attach", flag.ExitOnError)
+	pid := fs.Int("pid", 0, "pid of the target process")
+	sample := fs.Duration("sample", 10*time.Second, "how long to sample for")
+	window := fs.Duration("window", 100*time.Millisecond, "width of each utilization window")
+	perThread := fs.Bool("per-thread", false, "emit one utilization series per OS thread instead of a single aggregate")
+	jsonOut := fs.Bool("json", false, "write one JSON sample per window to stdout")
+	fs.Parse(args)
+
+	if *pid <= 0 {
+		return fmt.Errorf("usage: cog attach --pid <pid> --sample <duration>")
+	}
+
+	opts := trace.Options{Window: *window, PerThread: *perThread}
+	col := trace.NewCollector(*pid, opts)
+	enc := json.NewEncoder(os.Stdout)
+	spark := map[int][]float64{}
+
+	err := col.Run(*sample, func(t int64, util map[int]float64) {
+		keys := make([]int, 0, len(util))
+		for k := range util {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+
+		if *perThread {
+			fmt.Fprintf(os.Stderr, "%5.1fs\n", float64(t)/1000)
+		}
+		for _, k := range keys {
+			if *jsonOut {
+				enc.Encode(trace.Series{Thread: k, Samples: []trace.Sample{{Time: t, Util: util[k]}}})
+			}
+			spark[k] = appendCapped(spark[k], util[k], 80)
+			label := "aggregate"
+			if k >= 0 {
+				label = fmt.Sprintf("thread%d", k)
+			}
+			if *perThread {
+				fmt.Fprintf(os.Stderr, "  %-8s %s  %4.0f%%\n", label, trace.Sparkline(spark[k]), util[k]*100)
+			}
+		}
+		if !*perThread {
+			fmt.Fprintf(os.Stderr, "\r%5.1fs  %s  %4.0f%%", float64(t)/1000, trace.Sparkline(spark[-1]), util[-1]*100)
+		}
+	})
+	fmt.Fprintln(os.Stderr)
+	return err
+}
+
+func appendCapped(vals []float64, v float64, max int) []float64 {
+	vals = append(vals, v)
+	if len(vals) > max {
+		vals = vals[len(vals)-max:]
+	}
+	return vals
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/trycog/cog-cli/pkg/inspect"
+)
+
+func init() {
+	register(command{
+		name:  "inspect",
+		usage: "render in-scope variables at an instrumented source line as a typed tree",
+		run:   runInspect,
+	})
+}
+
+func runInspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	depth := fs.Int("depth", 2, "max struct/pointer nesting depth (0 = unlimited)")
+	withGlobals := fs.Bool("globals", false, "include package-level variables")
+	format := fs.String("format", "text", "output format: text, json, or dot")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		return fmt.Errorf("usage: cog inspect [flags] <file>:<line>")
+	}
+	file, line, err := parseLoc(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	vars, err := inspect.Snapshot(file, line, *withGlobals)
+	if err != nil {
+		return err
+	}
+
+	var r inspect.Renderer
+	switch *format {
+	case "text":
+		r = inspect.TextRenderer{}
+	case "json":
+		r = inspect.JSONRenderer{}
+	case "dot":
+		r = inspect.DotRenderer{}
+	default:
+		return fmt.Errorf("inspect: unknown --format %q", *format)
+	}
+
+	out, err := r.Render(vars, inspect.Options{Depth: *depth, Globals: *withGlobals})
+	if err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
+
+func parseLoc(s string) (file string, line int, err error) {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return "", 0, fmt.Errorf("inspect: expected <file>:<line>, got %q", s)
+	}
+	line, err = strconv.Atoi(s[i+1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("inspect: invalid line in %q: %w", s, err)
+	}
+	return s[:i], line, nil
+}
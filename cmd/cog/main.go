@@ -0,0 +1,54 @@
+// Command cog is a debugging toolkit for Go binaries: crash
+// postmortems, attach/sample profiling, variable inspection, and
+// record/replay.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// command is a single cog subcommand. Subcommand files register
+// themselves via init() so main stays a plain dispatcher.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands []command
+
+func register(c command) {
+	commands = append(commands, c)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	name := os.Args[1]
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		if err := c.run(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "cog:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "cog: unknown command %q\n\n", name)
+	printUsage()
+	os.Exit(1)
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: cog <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", c.name, c.usage)
+	}
+}
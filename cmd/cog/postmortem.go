@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/trycog/cog-cli/pkg/postmortem"
+)
+
+func init() {
+	register(command{
+		name:  "postmortem",
+		usage: "run a binary under a crash supervisor and report the panic site",
+		run:   runPostmortem,
+	})
+}
+
+func runPostmortem(args []string) error {
+	fs := flag.NewFlagSet("postmortem", flag.ExitOnError)
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		return fmt.Errorf("usage: cog postmortem <binary> [args...]")
+	}
+
+	sup := postmortem.New(fs.Arg(0), fs.Args()[1:]...)
+	report, err := sup.Run()
+	if err != nil {
+		return err
+	}
+	if report == nil {
+		fmt.Println("cog: target exited cleanly, nothing to report")
+		return nil
+	}
+	printReport(report)
+	return nil
+}
+
+func printReport(r *postmortem.Report) {
+	fmt.Printf("%s: %s\n\n", r.Signal, r.Message)
+	for i, f := range r.Frames {
+		fmt.Printf("#%d %s\n    %s:%d  %s\n", i, f.Function, f.File, f.Line, f.Source)
+		for _, v := range f.Vars {
+			fmt.Printf("        %s=%s\n", v.Name, v.Value)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/gob"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/trycog/cog-cli/pkg/replay"
+)
+
+func init() {
+	register(command{
+		name:  "record",
+		usage: "journal a function's execution for later replay",
+		run:   runRecord,
+	})
+}
+
+func runRecord(args []string) error {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	out := fs.String("out", "cog.journal", "path to write the recorded journal to")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		return fmt.Errorf("usage: cog record [flags] <file> <func> [int-args...]")
+	}
+	file, fn := fs.Arg(0), fs.Arg(1)
+
+	var callArgs []int
+	for _, raw := range fs.Args()[2:] {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("record: argument %q is not an int: %w", raw, err)
+		}
+		callArgs = append(callArgs, v)
+	}
+
+	rec, err := replay.NewRecorder(file)
+	if err != nil {
+		return err
+	}
+	journal, result, err := rec.Record(fn, callArgs...)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+	defer f.Close()
+	if err := gob.NewEncoder(f).Encode(journal.Events()); err != nil {
+		return fmt.Errorf("record: writing %s: %w", *out, err)
+	}
+
+	fmt.Printf("%s(%v) = %d\n", fn, callArgs, result)
+	fmt.Printf("wrote %d events to %s\n", journal.Len(), *out)
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/gob"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/trycog/cog-cli/pkg/replay"
+)
+
+func init() {
+	register(command{
+		name:  "replay",
+		usage: "step forward/backward through a recorded journal",
+		run:   runReplay,
+	})
+}
+
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	in := fs.String("in", "cog.journal", "path to a journal written by cog record")
+	reverseToVar := fs.String("reverse-to", "", "reverse-continue until this variable changes (name=value)")
+	frames := fs.String("frames", "", "print every active frame of this function at the final step")
+	fs.Parse(args)
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	var events []replay.Event
+	if err := gob.NewDecoder(f).Decode(&events); err != nil {
+		f.Close()
+		return fmt.Errorf("replay: reading %s: %w", *in, err)
+	}
+	f.Close()
+
+	journal := replay.FromEvents(events)
+	p := replay.NewPlayer(journal)
+
+	name, wantVal := splitNameValue(*reverseToVar)
+	if name != "" {
+		for {
+			if _, ok := p.Step(); !ok {
+				break
+			}
+		}
+		e, found := p.ReverseContinueUntil(func(e replay.Event) bool {
+			return e.Kind == replay.EventAssign && e.Var == name && (wantVal == "" || e.New == wantVal)
+		})
+		if !found {
+			return fmt.Errorf("replay: %s never matched going backward", *reverseToVar)
+		}
+		fmt.Printf("step %d: %s %s->%s (line %d)\n", e.Step, e.Var, e.Old, e.New, e.Line)
+	}
+
+	if *frames != "" {
+		for _, e := range p.FramesAt(journal.Len()-1, *frames) {
+			fmt.Printf("frame: %s(%v) @ step %d\n", e.Func, e.Args, e.Step)
+		}
+	}
+
+	if name == "" && *frames == "" {
+		for i := 0; i < journal.Len(); i++ {
+			e, _ := journal.At(i)
+			printEvent(e)
+		}
+	}
+	return nil
+}
+
+func printEvent(e replay.Event) {
+	switch e.Kind {
+	case replay.EventCall:
+		fmt.Printf("%d: call %s(%v)\n", e.Step, e.Func, e.Args)
+	case replay.EventReturn:
+		fmt.Printf("%d: return %s -> %s\n", e.Step, e.Func, e.Result)
+	case replay.EventAssign:
+		fmt.Printf("%d: %s=%s->%s (line %d) %v\n", e.Step, e.Var, e.Old, e.New, e.Line, e.Locals)
+	}
+}
+
+func splitNameValue(s string) (name, value string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '=' {
+			return s[:i], s[i+1:]
+		}
+	}
+	return s, ""
+}
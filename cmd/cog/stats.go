@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/trycog/cog-cli/pkg/procstats"
+)
+
+func init() {
+	register(command{
+		name:  "stats",
+		usage: "sample a target's resource usage alongside its instrumented-line hits",
+		run:   runStats,
+	})
+}
+
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	source := fs.String("source", "", "annotated source file the target was built from, for line correlation")
+	interval := fs.Duration("interval", time.Second, "resource sampling interval")
+	debugAddr := fs.String("debug-addr", "", "host:port of a net/http/pprof endpoint the target is serving, for goroutine/heap sampling")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || *source == "" {
+		return fmt.Errorf("usage: cog stats --source <file> <binary> [args...]")
+	}
+
+	mon := procstats.NewMonitor(procstats.NewSampler(), *interval)
+	mon.DebugAddr = *debugAddr
+	enc := json.NewEncoder(os.Stdout)
+
+	summary, err := mon.Run(fs.Arg(0), fs.Args()[1:], *source, func(e procstats.Event) {
+		enc.Encode(e)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "\npeak RSS: %d bytes\n", summary.PeakRSSBytes)
+	fmt.Fprintf(os.Stderr, "mean CPU: %.1f%%\n", summary.MeanCPUPercent)
+	fmt.Fprintln(os.Stderr, "line hits:")
+	for line, count := range summary.LineHits {
+		fmt.Fprintf(os.Stderr, "  line %d: %d\n", line, count)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package inspect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DotRenderer emits the pointer graph as Graphviz dot, so cycles and
+// shared structure are visible at a glance instead of flattened.
+type DotRenderer struct{}
+
+func (DotRenderer) Render(vars []Value, opts Options) (string, error) {
+	var b strings.Builder
+	b.WriteString("digraph vars {\n  rankdir=LR;\n  node [shape=record];\n")
+
+	visited := map[visitKey]bool{}
+	for i, v := range vars {
+		root := fmt.Sprintf("root%d", i)
+		fmt.Fprintf(&b, "  %s [label=%q];\n", root, v.Name)
+		edgeDot(&b, root, v, opts.Depth, visited)
+	}
+
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+func edgeDot(b *strings.Builder, from string, v Value, depth int, visited map[visitKey]bool) {
+	switch v.Kind {
+	case KindScalar, KindNil, KindUnknown:
+		node := nodeID(v)
+		fmt.Fprintf(b, "  %s [label=%q];\n", node, renderLeaf(v))
+		fmt.Fprintf(b, "  %s -> %s;\n", from, node)
+
+	case KindStruct:
+		node := nodeID(v)
+		key := visitKey{v.Addr, v.Type}
+		if v.Addr != 0 && visited[key] {
+			fmt.Fprintf(b, "  %s -> %s [label=\"cycle\"];\n", from, node)
+			return
+		}
+		if v.Addr != 0 {
+			visited[key] = true
+		}
+		fmt.Fprintf(b, "  %s [label=%q];\n", node, structLabel(v))
+		fmt.Fprintf(b, "  %s -> %s;\n", from, node)
+		if depth != 1 {
+			for _, f := range v.Fields {
+				if f.Kind == KindPointer || f.Kind == KindStruct {
+					edgeDot(b, node, f, nextDepth(depth), visited)
+				}
+			}
+		}
+
+	case KindPointer:
+		node := nodeID(v)
+		if v.Elem == nil {
+			fmt.Fprintf(b, "  %s [label=\"nil\"];\n", node)
+			fmt.Fprintf(b, "  %s -> %s;\n", from, node)
+			return
+		}
+		key := visitKey{v.Addr, v.Type}
+		if v.Addr != 0 && visited[key] {
+			fmt.Fprintf(b, "  %s -> %s [label=\"cycle\"];\n", from, node)
+			return
+		}
+		if v.Addr != 0 {
+			visited[key] = true
+		}
+		if depth == 1 {
+			fmt.Fprintf(b, "  %s [label=%q];\n", node, v.Elem.Type)
+			fmt.Fprintf(b, "  %s -> %s;\n", from, node)
+			return
+		}
+		edgeDot(b, from, *v.Elem, nextDepth(depth), visited)
+	}
+}
+
+func nodeID(v Value) string {
+	return fmt.Sprintf("n_%s_%d", sanitizeID(v.Type), v.Addr)
+}
+
+func sanitizeID(s string) string {
+	return strings.NewReplacer(".", "_", "*", "p", "[", "_", "]", "_").Replace(s)
+}
+
+func renderLeaf(v Value) string {
+	switch v.Kind {
+	case KindNil:
+		return "<nil>"
+	case KindUnknown:
+		return "<unknown>"
+	default:
+		return v.Scalar
+	}
+}
+
+func structLabel(v Value) string {
+	names := make([]string, len(v.Fields))
+	for i, f := range v.Fields {
+		names[i] = f.Name
+	}
+	return v.Type + "|" + strings.Join(names, "|")
+}
@@ -0,0 +1,84 @@
+// Package inspect renders in-scope variables at a breakpoint or trace
+// event as a typed tree: scalars inline, structs expanded field by
+// field, and pointers followed one hop with cycle detection.
+//
+// A Value tree is deliberately decoupled from how it was populated —
+// Snapshot builds one today from static source declarations (the same
+// call-site analysis cog's postmortem command uses), so cog inspect
+// works against the fixtures without a live debugger backend. A
+// future DWARF- or trace-backed source can produce the same tree and
+// reuse every Renderer unchanged.
+package inspect
+
+// Kind is the shape of a Value.
+type Kind int
+
+const (
+	KindScalar Kind = iota
+	KindStruct
+	KindPointer
+	KindNil
+	// KindUnknown marks a variable Snapshot found (a parameter, or a
+	// local whose initializer isn't a literal it can statically
+	// evaluate) but cannot assign a value to without a live debugger
+	// backend. Renderers show it explicitly rather than omitting the
+	// variable, which would look like it was never in scope at all.
+	KindUnknown
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindScalar:
+		return "scalar"
+	case KindStruct:
+		return "struct"
+	case KindPointer:
+		return "pointer"
+	case KindNil:
+		return "nil"
+	case KindUnknown:
+		return "unknown"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON renders Kind as its name rather than an int, so the JSON
+// schema is self-describing.
+func (k Kind) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + k.String() + `"`), nil
+}
+
+// Value is one node of a rendered variable tree.
+type Value struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Kind Kind   `json:"kind"`
+
+	// Addr identifies the underlying storage for cycle detection.
+	// Two Values with the same (Addr, Type) are the same object.
+	Addr uintptr `json:"addr,omitempty"`
+
+	Scalar string  `json:"scalar,omitempty"` // set when Kind == KindScalar
+	Fields []Value `json:"fields,omitempty"` // set when Kind == KindStruct
+	Elem   *Value  `json:"elem,omitempty"`   // set when Kind == KindPointer and non-nil
+}
+
+// Options controls how deep a Renderer walks a Value tree.
+type Options struct {
+	// Depth bounds struct/pointer nesting. Zero means unlimited.
+	Depth int
+	// Globals includes package-level variables alongside locals.
+	Globals bool
+}
+
+// Renderer turns a set of variables into an output representation.
+type Renderer interface {
+	Render(vars []Value, opts Options) (string, error)
+}
+
+// visitKey identifies an already-rendered object for cycle detection.
+type visitKey struct {
+	addr uintptr
+	typ  string
+}
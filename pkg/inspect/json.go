@@ -0,0 +1,55 @@
+package inspect
+
+import "encoding/json"
+
+// JSONRenderer marshals a Value tree using the same field names and
+// Kind strings as the Go type, so downstream tooling (a TUI, a web
+// view) can consume it without a bespoke schema.
+type JSONRenderer struct{}
+
+func (JSONRenderer) Render(vars []Value, opts Options) (string, error) {
+	visited := map[visitKey]bool{}
+	safe := make([]Value, len(vars))
+	for i, v := range vars {
+		safe[i] = sanitize(v, opts.Depth, visited)
+	}
+	b, err := json.MarshalIndent(safe, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// sanitize returns a depth-bounded, cycle-broken copy of v suitable
+// for json.Marshal: revisited (addr, type) pairs collapse to a nil
+// Elem/empty Fields rather than forming a real Go-level cycle.
+func sanitize(v Value, depth int, visited map[visitKey]bool) Value {
+	out := v
+	out.Fields = nil
+	out.Elem = nil
+
+	key := visitKey{v.Addr, v.Type}
+	cyclic := v.Addr != 0 && visited[key]
+	if v.Addr != 0 {
+		visited[key] = true
+	}
+
+	switch v.Kind {
+	case KindStruct:
+		if cyclic || depth == 1 {
+			return out
+		}
+		out.Fields = make([]Value, len(v.Fields))
+		for i, f := range v.Fields {
+			out.Fields[i] = sanitize(f, nextDepth(depth), visited)
+		}
+
+	case KindPointer:
+		if v.Elem == nil || cyclic || depth == 1 {
+			return out
+		}
+		elem := sanitize(*v.Elem, nextDepth(depth), visited)
+		out.Elem = &elem
+	}
+	return out
+}
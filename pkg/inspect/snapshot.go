@@ -0,0 +1,462 @@
+package inspect
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+
+	"github.com/trycog/cog-cli/pkg/lineinfo"
+)
+
+// Snapshot builds a Value tree for the locals in scope at line of
+// file (and, if globals is set, every package-level var), the same
+// way cog's postmortem command recovers variables without a live
+// debugger: by statically reading the literal assignments that
+// produced them. line must be one of the file's annotated "// line N"
+// markers.
+func Snapshot(file string, line int, globals bool) ([]Value, error) {
+	li, err := lineinfo.Load(file)
+	if err != nil {
+		return nil, err
+	}
+	if !li.Annotated(line) {
+		return nil, fmt.Errorf("inspect: %s:%d is not an instrumented line", file, line)
+	}
+
+	fset := token.NewFileSet()
+	src, err := parser.ParseFile(fset, file, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("inspect: %w", err)
+	}
+
+	s := &snapshotter{fset: fset, addrs: map[string]uintptr{}, nextAddr: 1, funcs: map[string]*ast.FuncDecl{}}
+
+	var fn *ast.FuncDecl
+	for _, d := range src.Decls {
+		f, ok := d.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		s.funcs[f.Name.Name] = f
+		if f.Body != nil && withinRange(fset, f.Body, line) {
+			fn = f
+		}
+	}
+
+	var vars []Value
+	if globals {
+		vars = append(vars, s.globalValues(src)...)
+	}
+	if fn != nil {
+		vars = append(vars, s.localValues(fn, line)...)
+	}
+	return vars, nil
+}
+
+type snapshotter struct {
+	fset     *token.FileSet
+	addrs    map[string]uintptr
+	nextAddr uintptr
+	funcs    map[string]*ast.FuncDecl
+}
+
+func (s *snapshotter) addrOf(name string) uintptr {
+	if a, ok := s.addrs[name]; ok {
+		return a
+	}
+	a := s.nextAddr
+	s.nextAddr++
+	s.addrs[name] = a
+	return a
+}
+
+func withinRange(fset *token.FileSet, n ast.Node, line int) bool {
+	start := fset.Position(n.Pos()).Line
+	end := fset.Position(n.End()).Line
+	return line >= start && line <= end
+}
+
+// localValues walks fn's statements up to line, keeping the last
+// assigned value for each name. Calls that mutate a pointer parameter
+// (e.g. modify(&x, 3) where modify does *val += delta) are simulated
+// too, so a local passed by address doesn't read as stale once the
+// call has run.
+//
+// fn's parameters are seeded in first, as KindUnknown: without a live
+// debugger backend there's no call site bound to "the current call"
+// of fn, so a parameter's runtime value can't be recovered — but it
+// is in scope, and showing it as unknown beats not showing it at all.
+func (s *snapshotter) localValues(fn *ast.FuncDecl, line int) []Value {
+	byName := map[string]Value{}
+	var order []string
+
+	for _, p := range s.paramValues(fn.Type.Params) {
+		order = append(order, p.Name)
+		byName[p.Name] = p
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			if s.fset.Position(stmt.Pos()).Line > line {
+				return true
+			}
+			for i, lhs := range stmt.Lhs {
+				ident, ok := lhs.(*ast.Ident)
+				if !ok || ident.Name == "_" || i >= len(stmt.Rhs) {
+					continue
+				}
+				if _, seen := byName[ident.Name]; !seen {
+					order = append(order, ident.Name)
+				}
+				byName[ident.Name] = s.valueOf(ident.Name, stmt.Rhs[i], byName)
+			}
+
+		case *ast.ExprStmt:
+			if s.fset.Position(stmt.Pos()).Line > line {
+				return true
+			}
+			if call, ok := stmt.X.(*ast.CallExpr); ok {
+				s.applyCallEffect(call, byName)
+			}
+		}
+		return true
+	})
+
+	vars := make([]Value, 0, len(order))
+	for _, name := range order {
+		vars = append(vars, byName[name])
+	}
+	return vars
+}
+
+// applyCallEffect statically simulates a call to a function, defined
+// in the same file, whose body mutates one of its pointer parameters
+// via a single "*param op= expr" statement (the same pattern the
+// postmortem snapshot path already assumes for recovered locals). On
+// a match it updates byName[target] in place, where target is the
+// variable behind the "&target" argument at the mutated parameter's
+// position.
+func (s *snapshotter) applyCallEffect(call *ast.CallExpr, byName map[string]Value) {
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return
+	}
+	fn, ok := s.funcs[ident.Name]
+	if !ok || fn.Body == nil {
+		return
+	}
+	params := flattenParams(fn.Type.Params)
+
+	for _, bodyStmt := range fn.Body.List {
+		assign, ok := bodyStmt.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			continue
+		}
+		star, ok := assign.Lhs[0].(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		paramIdent, ok := star.X.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		paramIdx := indexOf(params, paramIdent.Name)
+		if paramIdx < 0 || paramIdx >= len(call.Args) {
+			continue
+		}
+		target, ok := derefArg(call.Args[paramIdx])
+		if !ok {
+			continue
+		}
+		old, ok := byName[target]
+		if !ok || old.Kind != KindScalar {
+			continue
+		}
+		delta, ok := evalArgInt(assign.Rhs[0], params, call.Args)
+		if !ok {
+			continue
+		}
+		scalar, ok := applyAssignOp(old.Scalar, assign.Tok, delta)
+		if !ok {
+			continue
+		}
+		old.Scalar = scalar
+		byName[target] = old
+	}
+}
+
+// paramValues returns one KindUnknown Value per named parameter in
+// fl, in declaration order.
+func (s *snapshotter) paramValues(fl *ast.FieldList) []Value {
+	if fl == nil {
+		return nil
+	}
+	var vals []Value
+	for _, f := range fl.List {
+		typeName := exprTypeName(f.Type)
+		for _, n := range f.Names {
+			if n.Name == "_" {
+				continue
+			}
+			vals = append(vals, Value{Name: n.Name, Type: typeName, Kind: KindUnknown, Addr: s.addrOf(n.Name)})
+		}
+	}
+	return vals
+}
+
+// exprTypeName renders a parameter's type expression as source text
+// for display; it doesn't need to be exhaustive since the value
+// itself is already unknown.
+func exprTypeName(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + exprTypeName(t.X)
+	default:
+		return "unknown"
+	}
+}
+
+// flattenParams expands a function's parameter list into one name per
+// position, matching how call arguments line up positionally.
+func flattenParams(fl *ast.FieldList) []string {
+	if fl == nil {
+		return nil
+	}
+	var names []string
+	for _, f := range fl.List {
+		if len(f.Names) == 0 {
+			names = append(names, "")
+			continue
+		}
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+	}
+	return names
+}
+
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// derefArg reports whether arg is "&ident" and, if so, ident's name.
+func derefArg(arg ast.Expr) (string, bool) {
+	u, ok := arg.(*ast.UnaryExpr)
+	if !ok || u.Op != token.AND {
+		return "", false
+	}
+	id, ok := u.X.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	return id.Name, true
+}
+
+// evalArgInt resolves expr to an int literal, following it through to
+// the matching call argument when expr is itself a parameter name.
+func evalArgInt(expr ast.Expr, params []string, args []ast.Expr) (int, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		idx := indexOf(params, e.Name)
+		if idx < 0 || idx >= len(args) {
+			return 0, false
+		}
+		return literalInt(args[idx])
+	case *ast.BasicLit:
+		return literalInt(e)
+	default:
+		return 0, false
+	}
+}
+
+func literalInt(expr ast.Expr) (int, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+	n, err := strconv.Atoi(lit.Value)
+	return n, err == nil
+}
+
+// applyAssignOp applies a compound assignment operator (+=, -=, *=,
+// /=) to oldScalar and returns the result as a Value.Scalar string.
+func applyAssignOp(oldScalar string, tok token.Token, delta int) (string, bool) {
+	old, err := strconv.Atoi(oldScalar)
+	if err != nil {
+		return "", false
+	}
+	switch tok {
+	case token.ADD_ASSIGN:
+		old += delta
+	case token.SUB_ASSIGN:
+		old -= delta
+	case token.MUL_ASSIGN:
+		old *= delta
+	case token.QUO_ASSIGN:
+		if delta == 0 {
+			return "", false
+		}
+		old /= delta
+	default:
+		return "", false
+	}
+	return strconv.Itoa(old), true
+}
+
+// globalValues reads every package-level var decl with a literal
+// initializer.
+func (s *snapshotter) globalValues(file *ast.File) []Value {
+	var vars []Value
+	for _, d := range file.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range vs.Names {
+				if i >= len(vs.Values) {
+					continue
+				}
+				vars = append(vars, s.valueOf(name.Name, vs.Values[i], nil))
+			}
+		}
+	}
+	return vars
+}
+
+// valueOf interprets a single assignment's RHS expression as a Value
+// named name. known holds already-resolved locals (including params,
+// seeded as KindUnknown), used to follow "&x" pointer expressions and
+// arithmetic back to x's Value. Whatever the expression shape, this
+// always produces a Value: one it can't statically evaluate (a call,
+// a selector, arithmetic over an unknown operand) still comes back as
+// KindUnknown, rather than silently dropping the variable from the
+// snapshot.
+func (s *snapshotter) valueOf(name string, expr ast.Expr, known map[string]Value) Value {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return Value{Name: name, Type: basicType(e), Kind: KindScalar, Scalar: e.Value, Addr: s.addrOf(name)}
+
+	case *ast.CompositeLit:
+		typeName := fmt.Sprintf("%v", e.Type)
+		if id, ok := e.Type.(*ast.Ident); ok {
+			typeName = id.Name
+		}
+		v := Value{Name: name, Type: typeName, Kind: KindStruct, Addr: s.addrOf(name)}
+		for _, elt := range e.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			v.Fields = append(v.Fields, s.valueOf(key.Name, kv.Value, known))
+		}
+		return v
+
+	case *ast.UnaryExpr:
+		if e.Op != token.AND {
+			return s.unknownValue(name)
+		}
+		id, ok := e.X.(*ast.Ident)
+		if !ok {
+			return s.unknownValue(name)
+		}
+		target, ok := known[id.Name]
+		if !ok {
+			target = s.unknownValue(id.Name)
+		}
+		return Value{Name: name, Type: "*" + target.Type, Kind: KindPointer, Addr: s.addrOf(name), Elem: &target}
+
+	case *ast.BinaryExpr:
+		lhs, lok := intOperand(e.X, known)
+		rhs, rok := intOperand(e.Y, known)
+		if lok && rok {
+			if result, ok := applyBinaryOp(lhs, e.Op, rhs); ok {
+				return Value{Name: name, Type: "int", Kind: KindScalar, Scalar: strconv.Itoa(result), Addr: s.addrOf(name)}
+			}
+		}
+		return s.unknownValue(name)
+
+	default:
+		return s.unknownValue(name)
+	}
+}
+
+// unknownValue is the KindUnknown Value for a variable Snapshot can
+// see but can't statically resolve.
+func (s *snapshotter) unknownValue(name string) Value {
+	return Value{Name: name, Type: "unknown", Kind: KindUnknown, Addr: s.addrOf(name)}
+}
+
+// intOperand resolves expr to an int, either as a literal or as an
+// already-known int-scalar local.
+func intOperand(expr ast.Expr, known map[string]Value) (int, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return literalInt(e)
+	case *ast.Ident:
+		v, ok := known[e.Name]
+		if !ok || v.Kind != KindScalar {
+			return 0, false
+		}
+		n, err := strconv.Atoi(v.Scalar)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// applyBinaryOp applies a binary arithmetic operator to two known
+// ints.
+func applyBinaryOp(a int, op token.Token, b int) (int, bool) {
+	switch op {
+	case token.ADD:
+		return a + b, true
+	case token.SUB:
+		return a - b, true
+	case token.MUL:
+		return a * b, true
+	case token.QUO:
+		if b == 0 {
+			return 0, false
+		}
+		return a / b, true
+	case token.REM:
+		if b == 0 {
+			return 0, false
+		}
+		return a % b, true
+	default:
+		return 0, false
+	}
+}
+
+func basicType(lit *ast.BasicLit) string {
+	switch lit.Kind {
+	case token.INT:
+		return "int"
+	case token.FLOAT:
+		return "float64"
+	case token.STRING:
+		return "string"
+	default:
+		return "unknown"
+	}
+}
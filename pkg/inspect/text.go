@@ -0,0 +1,81 @@
+package inspect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TextRenderer flattens a Value tree into "path=value" pairs, one per
+// line, e.g. "pt.X=100" / "pt.Y=200" / "pt.Name=\"origin\"".
+type TextRenderer struct{}
+
+func (TextRenderer) Render(vars []Value, opts Options) (string, error) {
+	var b strings.Builder
+	visited := map[visitKey]bool{}
+	for _, v := range vars {
+		renderText(&b, v.Name, v, opts.Depth, visited)
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func renderText(b *strings.Builder, path string, v Value, depth int, visited map[visitKey]bool) {
+	switch v.Kind {
+	case KindNil:
+		fmt.Fprintf(b, "%s=<nil>", path)
+
+	case KindScalar:
+		fmt.Fprintf(b, "%s=%s", path, v.Scalar)
+
+	case KindUnknown:
+		fmt.Fprintf(b, "%s=<unknown>", path)
+
+	case KindStruct:
+		key := visitKey{v.Addr, v.Type}
+		if v.Addr != 0 && visited[key] {
+			fmt.Fprintf(b, "%s=<cycle %s>", path, v.Type)
+			return
+		}
+		if v.Addr != 0 {
+			visited[key] = true
+		}
+		if depth == 1 {
+			fmt.Fprintf(b, "%s=%s{...}", path, v.Type)
+			return
+		}
+		sep := ""
+		for _, f := range v.Fields {
+			b.WriteString(sep)
+			renderText(b, path+"."+f.Name, f, nextDepth(depth), visited)
+			sep = " "
+		}
+
+	case KindPointer:
+		if v.Elem == nil {
+			fmt.Fprintf(b, "%s=<nil>", path)
+			return
+		}
+		key := visitKey{v.Addr, v.Type}
+		if v.Addr != 0 && visited[key] {
+			fmt.Fprintf(b, "%s=<cycle %s>", path, v.Type)
+			return
+		}
+		if v.Addr != 0 {
+			visited[key] = true
+		}
+		if depth == 1 {
+			fmt.Fprintf(b, "%s=&%s{...}", path, v.Elem.Type)
+			return
+		}
+		renderText(b, "*"+path, *v.Elem, nextDepth(depth), visited)
+	}
+}
+
+// nextDepth decrements a bounded depth, leaving an unlimited (zero)
+// depth untouched.
+func nextDepth(depth int) int {
+	if depth <= 0 {
+		return depth
+	}
+	return depth - 1
+}
@@ -0,0 +1,86 @@
+// Package lineinfo reads Go source files annotated with trailing
+// "// line N" comments and maps runtime events (panics, trace samples,
+// breakpoints) back to the exact source line and text they came from.
+//
+// The annotation convention is purely textual: a comment of the form
+// "// line N" on physical line N confirms that line is one the rest of
+// the cog toolchain can reason about. Unannotated lines are still
+// tracked (for context/excerpts) but are not treated as instrumented
+// points.
+package lineinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+var annotationRe = regexp.MustCompile(`//\s*line\s+(\d+)\b`)
+
+// File is a loaded, line-indexed source file.
+type File struct {
+	Path   string
+	Lines  []string     // 1-indexed via Lines[line-1]
+	Marked map[int]bool // lines carrying a "// line N" annotation
+}
+
+// Load reads path and indexes its lines and annotations.
+func Load(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("lineinfo: %w", err)
+	}
+	defer f.Close()
+
+	file := &File{Path: path, Marked: map[int]bool{}}
+	sc := bufio.NewScanner(f)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		text := sc.Text()
+		file.Lines = append(file.Lines, text)
+		if m := annotationRe.FindStringSubmatch(text); m != nil {
+			if n, err := strconv.Atoi(m[1]); err == nil && n == lineNo {
+				file.Marked[lineNo] = true
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("lineinfo: %w", err)
+	}
+	return file, nil
+}
+
+// Line returns the raw text of the given 1-indexed line, or "" if out
+// of range.
+func (f *File) Line(n int) string {
+	if n < 1 || n > len(f.Lines) {
+		return ""
+	}
+	return f.Lines[n-1]
+}
+
+// Annotated reports whether line n carries a "// line n" marker.
+func (f *File) Annotated(n int) bool {
+	return f.Marked[n]
+}
+
+// Excerpt returns up to context lines of source centered on n, each
+// prefixed with its line number.
+func (f *File) Excerpt(n, context int) []string {
+	start := n - context
+	if start < 1 {
+		start = 1
+	}
+	end := n + context
+	if end > len(f.Lines) {
+		end = len(f.Lines)
+	}
+	out := make([]string, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		out = append(out, fmt.Sprintf("%4d| %s", i, f.Line(i)))
+	}
+	return out
+}
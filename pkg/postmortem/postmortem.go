@@ -0,0 +1,307 @@
+// Package postmortem runs a target binary under a supervisor that
+// survives its crash, then renders the panic (or fatal signal) as a
+// per-frame report: source excerpt plus a best-effort snapshot of the
+// variables in scope at each frame.
+//
+// Variable values are not read out of a live process or a core dump —
+// there is no ptrace/DWARF reader here yet. Instead each frame's
+// bindings are recovered statically: call-site argument literals are
+// matched against the callee's parameter names, and never-assigned
+// pointer/interface locals are reported as nil. That covers exactly the
+// crash-fixture shapes cog ships with (divide-by-zero, nil deref,
+// explicit panic) without requiring a real debugger backend.
+package postmortem
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/trycog/cog-cli/pkg/lineinfo"
+)
+
+// VarBinding is a single name=value pair recovered for a frame.
+type VarBinding struct {
+	Name  string
+	Value string
+}
+
+// Frame is one entry of the unwound, crash-site-to-caller stack.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+	Source   string
+	Vars     []VarBinding
+}
+
+// Report is the rendered result of a single supervised run that ended
+// in a crash.
+type Report struct {
+	Signal  string // "panic", "SIGSEGV", "SIGFPE", or "exit"
+	Message string
+	Frames  []Frame
+}
+
+// Supervisor runs a target binary and, if it crashes, produces a
+// Report instead of just an exit code.
+type Supervisor struct {
+	BinPath string
+	Args    []string
+}
+
+// New returns a Supervisor for binPath invoked with args.
+func New(binPath string, args ...string) *Supervisor {
+	return &Supervisor{BinPath: binPath, Args: args}
+}
+
+var (
+	panicLineRe = regexp.MustCompile(`^panic:\s*(.+)$`)
+	sigLineRe   = regexp.MustCompile(`\[signal (SIG\w+):`)
+	frameFuncRe = regexp.MustCompile(`^(\S+)\(`)
+	frameLocRe  = regexp.MustCompile(`^\s*(\S+\.go):(\d+)`)
+)
+
+// Run executes the target to completion. If it crashed, the returned
+// Report describes it; if it exited cleanly, Run returns (nil, nil).
+func (s *Supervisor) Run() (*Report, error) {
+	cmd := exec.Command(s.BinPath, s.Args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+	if runErr == nil {
+		return nil, nil
+	}
+
+	exitErr, ok := runErr.(*exec.ExitError)
+	if !ok {
+		return nil, fmt.Errorf("postmortem: starting %s: %w", s.BinPath, runErr)
+	}
+
+	signal, message := classify(exitErr, stderr.String())
+	frames := parseStack(stderr.String())
+	annotate(frames)
+
+	return &Report{Signal: signal, Message: message, Frames: frames}, nil
+}
+
+// classify turns the exit state and captured stderr into a unified
+// signal name and message, treating SIGSEGV/SIGFPE the same as a
+// runtime panic.
+func classify(exitErr *exec.ExitError, stderrText string) (signal, message string) {
+	if m := panicLineRe.FindStringSubmatch(firstLine(stderrText)); m != nil {
+		message = m[1]
+	}
+	if m := sigLineRe.FindStringSubmatch(stderrText); m != nil {
+		return m[1], message
+	}
+	if sig, ok := signalFromExit(exitErr); ok {
+		return sig, message
+	}
+	if message != "" {
+		return "panic", message
+	}
+	return "exit", exitErr.Error()
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// parseStack extracts (function, file, line) triples from a Go
+// goroutine dump, in innermost-first order.
+func parseStack(stderrText string) []Frame {
+	var frames []Frame
+	lines := strings.Split(stderrText, "\n")
+	for i := 0; i < len(lines); i++ {
+		fm := frameFuncRe.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if fm == nil || i+1 >= len(lines) {
+			continue
+		}
+		lm := frameLocRe.FindStringSubmatch(lines[i+1])
+		if lm == nil {
+			continue
+		}
+		line, err := strconv.Atoi(lm[2])
+		if err != nil {
+			continue
+		}
+		frames = append(frames, Frame{
+			Function: fm[1],
+			File:     lm[1],
+			Line:     line,
+		})
+		i++
+	}
+	return frames
+}
+
+// annotate fills in Source and Vars for each frame in place.
+func annotate(frames []Frame) {
+	for i := range frames {
+		f, err := lineinfo.Load(frames[i].File)
+		if err != nil {
+			continue
+		}
+		frames[i].Source = strings.TrimSpace(f.Line(frames[i].Line))
+
+		// The callee's bindings are recovered from its caller's
+		// call-site, one frame up.
+		if i+1 < len(frames) {
+			caller := frames[i+1]
+			frames[i].Vars = snapshotArgs(caller.File, caller.Line, funcName(frames[i].Function))
+		}
+		if len(frames[i].Vars) == 0 {
+			frames[i].Vars = snapshotNilLocals(frames[i].File, funcName(frames[i].Function))
+		}
+	}
+}
+
+// funcName strips a "pkg." qualifier, e.g. "main.divide" -> "divide".
+func funcName(qualified string) string {
+	if i := strings.LastIndexByte(qualified, '.'); i >= 0 {
+		return qualified[i+1:]
+	}
+	return qualified
+}
+
+// snapshotArgs statically matches the literal arguments of a call to
+// callee found on line of file against callee's declared parameter
+// names.
+func snapshotArgs(file string, line int, callee string) []VarBinding {
+	fset := token.NewFileSet()
+	src, err := parser.ParseFile(fset, file, nil, 0)
+	if err != nil {
+		return nil
+	}
+
+	params := paramNames(src, callee)
+	if params == nil {
+		return nil
+	}
+
+	var call *ast.CallExpr
+	ast.Inspect(src, func(n ast.Node) bool {
+		if call != nil {
+			return false
+		}
+		ce, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := ce.Fun.(*ast.Ident)
+		if !ok || ident.Name != callee {
+			return true
+		}
+		if fset.Position(ce.Pos()).Line != line {
+			return true
+		}
+		call = ce
+		return false
+	})
+	if call == nil || len(call.Args) != len(params) {
+		return nil
+	}
+
+	vars := make([]VarBinding, 0, len(params))
+	for i, arg := range call.Args {
+		vars = append(vars, VarBinding{Name: params[i], Value: literalText(arg)})
+	}
+	return vars
+}
+
+// paramNames returns the flattened parameter names of function name's
+// declaration in file, or nil if not found.
+func paramNames(file *ast.File, name string) []string {
+	var names []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != name || fn.Type.Params == nil {
+			continue
+		}
+		for _, field := range fn.Type.Params.List {
+			if len(field.Names) == 0 {
+				names = append(names, "_")
+				continue
+			}
+			for _, n := range field.Names {
+				names = append(names, n.Name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// snapshotNilLocals reports every pointer-typed local in fn that is
+// declared without an initializer, under the assumption that an
+// unassigned pointer is the nil being dereferenced.
+func snapshotNilLocals(file, fn string) []VarBinding {
+	fset := token.NewFileSet()
+	src, err := parser.ParseFile(fset, file, nil, 0)
+	if err != nil {
+		return nil
+	}
+
+	var decl *ast.FuncDecl
+	for _, d := range src.Decls {
+		if f, ok := d.(*ast.FuncDecl); ok && f.Name.Name == fn {
+			decl = f
+			break
+		}
+	}
+	if decl == nil || decl.Body == nil {
+		return nil
+	}
+
+	var vars []VarBinding
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		gd, ok := n.(*ast.GenDecl)
+		if !ok || gd.Tok != token.VAR {
+			return true
+		}
+		for _, spec := range gd.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok || len(vs.Values) != 0 || vs.Type == nil {
+				continue
+			}
+			if _, isPtr := vs.Type.(*ast.StarExpr); !isPtr {
+				continue
+			}
+			for _, n := range vs.Names {
+				vars = append(vars, VarBinding{Name: n.Name, Value: "<nil>"})
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// literalText renders a simple literal/unary-literal argument
+// expression as source text (e.g. 10, -1). Anything else — most
+// commonly a call site passing a variable rather than a literal — has
+// no statically-known value, so it renders as "<unknown>" rather than
+// the source identifier, which would misreport the identifier's name
+// as if it were its runtime value.
+func literalText(e ast.Expr) string {
+	switch v := e.(type) {
+	case *ast.BasicLit:
+		return v.Value
+	case *ast.UnaryExpr:
+		if _, ok := v.X.(*ast.BasicLit); ok {
+			return v.Op.String() + literalText(v.X)
+		}
+		return "<unknown>"
+	default:
+		return "<unknown>"
+	}
+}
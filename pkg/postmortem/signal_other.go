@@ -0,0 +1,11 @@
+//go:build !unix
+
+package postmortem
+
+import "os/exec"
+
+// signalFromExit has no signal to report on platforms without Unix
+// wait-status semantics (Windows processes don't terminate by signal).
+func signalFromExit(exitErr *exec.ExitError) (string, bool) {
+	return "", false
+}
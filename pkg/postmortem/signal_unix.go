@@ -0,0 +1,18 @@
+//go:build unix
+
+package postmortem
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// signalFromExit reports the terminating signal's name, if the target
+// was killed by one rather than exiting normally.
+func signalFromExit(exitErr *exec.ExitError) (string, bool) {
+	ws, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return "", false
+	}
+	return ws.Signal().String(), true
+}
@@ -0,0 +1,79 @@
+package procstats
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// readDebugStats reads goroutine count and heap-in-use bytes straight
+// from the target's own runtime, via the net/http/pprof endpoints it
+// exposes at addr (e.g. "localhost:6060") when it opts in with a
+// blank import of net/http/pprof and an http.ListenAndServe. Neither
+// figure is visible from outside the process, so there is no OS-level
+// way to read them without the target serving them itself.
+func readDebugStats(addr string) (goroutines int, heapInUse uint64, err error) {
+	goroutines, err = readGoroutineCount(addr)
+	if err != nil {
+		return 0, 0, err
+	}
+	heapInUse, err = readHeapInUse(addr)
+	if err != nil {
+		return 0, 0, err
+	}
+	return goroutines, heapInUse, nil
+}
+
+var goroutineTotalRe = regexp.MustCompile(`^goroutine profile: total (\d+)`)
+
+// readGoroutineCount parses the "goroutine profile: total N" header
+// line /debug/pprof/goroutine?debug=1 always starts with.
+func readGoroutineCount(addr string) (int, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/debug/pprof/goroutine?debug=1", addr))
+	if err != nil {
+		return 0, fmt.Errorf("procstats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	sc := bufio.NewScanner(resp.Body)
+	if sc.Scan() {
+		if m := goroutineTotalRe.FindStringSubmatch(sc.Text()); m != nil {
+			n, err := strconv.Atoi(m[1])
+			if err == nil {
+				return n, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("procstats: unexpected goroutine profile response from %s", addr)
+}
+
+var heapInUseRe = regexp.MustCompile(`^# HeapInuse = (\d+)`)
+
+// readHeapInUse parses the "# HeapInuse = N" line out of the
+// runtime.MemStats dump /debug/pprof/heap?debug=1 appends after the
+// profile itself.
+func readHeapInUse(addr string) (uint64, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/debug/pprof/heap?debug=1", addr))
+	if err != nil {
+		return 0, fmt.Errorf("procstats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := heapInUseRe.FindStringSubmatch(line); m != nil {
+			n, err := strconv.ParseUint(m[1], 10, 64)
+			if err == nil {
+				return n, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("procstats: HeapInuse not found in heap profile from %s", addr)
+}
@@ -0,0 +1,222 @@
+package procstats
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trycog/cog-cli/pkg/lineinfo"
+)
+
+// Monitor runs a target binary, sampling its resource usage on a
+// fixed cadence and watching its stdout for output produced by
+// annotated source lines.
+type Monitor struct {
+	Sampler  Sampler
+	Interval time.Duration
+
+	// DebugAddr, if set, is the host:port of a net/http/pprof endpoint
+	// the target process is serving; Monitor uses it to fill in
+	// Sample.Goroutines and Sample.HeapInUse. Left empty, those two
+	// fields stay zero.
+	DebugAddr string
+}
+
+// NewMonitor returns a Monitor using the platform Sampler. Set
+// DebugAddr on the result to also sample goroutine count and
+// heap-in-use from a target that opts in with net/http/pprof.
+func NewMonitor(s Sampler, interval time.Duration) *Monitor {
+	return &Monitor{Sampler: s, Interval: interval}
+}
+
+// Run launches binPath with args, merges resource samples and
+// sourcePath's instrumented-line hits into one timeline delivered to
+// onEvent as it happens, and returns the run's Summary once the
+// process exits.
+func (m *Monitor) Run(binPath string, args []string, sourcePath string, onEvent func(Event)) (Summary, error) {
+	matchers, err := literalMatchers(sourcePath)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	cmd := exec.Command(binPath, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return Summary{}, fmt.Errorf("procstats: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return Summary{}, fmt.Errorf("procstats: %w", err)
+	}
+	pid := cmd.Process.Pid
+
+	summary := Summary{LineHits: map[int]int{}}
+	var cpuTotal float64
+	var cpuSamples int
+
+	linesDone := make(chan struct{})
+	go func() {
+		defer close(linesDone)
+		sc := bufio.NewScanner(stdout)
+		for sc.Scan() {
+			text := sc.Text()
+			lines, ok := matchLine(matchers, text)
+			if !ok {
+				continue
+			}
+			for _, line := range lines {
+				summary.LineHits[line]++
+				onEvent(Event{Time: nowMillis(), Line: &LineHit{Time: nowMillis(), Line: line, Text: text}})
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+sampleLoop:
+	for {
+		select {
+		case <-ticker.C:
+			s, err := m.Sampler.Sample(pid)
+			if err != nil {
+				continue // process likely exiting between ticks
+			}
+			if m.DebugAddr != "" {
+				if goroutines, heapInUse, err := readDebugStats(m.DebugAddr); err == nil {
+					s.Goroutines = goroutines
+					s.HeapInUse = heapInUse
+				}
+			}
+			cpuTotal += s.CPUPercent
+			cpuSamples++
+			if s.RSSBytes > summary.PeakRSSBytes {
+				summary.PeakRSSBytes = s.RSSBytes
+			}
+			onEvent(Event{Time: s.Time, Resource: &s})
+		case runErr := <-exited:
+			<-linesDone
+			if cpuSamples > 0 {
+				summary.MeanCPUPercent = cpuTotal / float64(cpuSamples)
+			}
+			if runErr != nil {
+				if _, ok := runErr.(*exec.ExitError); !ok {
+					return summary, fmt.Errorf("procstats: %w", runErr)
+				}
+			}
+			break sampleLoop
+		}
+	}
+	return summary, nil
+}
+
+func nowMillis() int64 {
+	return time.Now().UnixMilli()
+}
+
+// literalMatcher attributes a stdout line back to one or more
+// annotated source lines. Most annotated lines have no observable
+// side effect of their own (e.g. "counter++ // line 12"), so a
+// matcher's prefix comes from the nearest following annotated line,
+// in the same function, that does have a fmt.Print* literal; lines
+// is every annotated line in between, inclusive, all of which are
+// reported as hit whenever that literal is seen.
+type literalMatcher struct {
+	prefix string
+	lines  []int
+}
+
+var quotedLiteralRe = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"`)
+
+// literalMatchers parses sourcePath to find every annotated line
+// within each function, in source order, then groups consecutive
+// literal-less lines onto the next line that has one.
+func literalMatchers(sourcePath string) ([]literalMatcher, error) {
+	li, err := lineinfo.Load(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	src, err := parser.ParseFile(fset, sourcePath, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("procstats: %w", err)
+	}
+
+	var matchers []literalMatcher
+	for _, d := range src.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		matchers = append(matchers, funcMatchers(fset, fn, li)...)
+	}
+	return matchers, nil
+}
+
+func funcMatchers(fset *token.FileSet, fn *ast.FuncDecl, li *lineinfo.File) []literalMatcher {
+	seen := map[int]bool{}
+	var lines []int
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		line := fset.Position(n.Pos()).Line
+		if li.Annotated(line) && !seen[line] {
+			seen[line] = true
+			lines = append(lines, line)
+		}
+		return true
+	})
+	sort.Ints(lines)
+
+	var matchers []literalMatcher
+	for i := 0; i < len(lines); {
+		group := []int{lines[i]}
+		prefix, ok := literalPrefix(li.Line(lines[i]))
+		i++
+		for !ok && i < len(lines) {
+			group = append(group, lines[i])
+			prefix, ok = literalPrefix(li.Line(lines[i]))
+			i++
+		}
+		if ok {
+			matchers = append(matchers, literalMatcher{prefix: prefix, lines: group})
+		}
+	}
+	return matchers
+}
+
+// literalPrefix extracts the fixed text before the first verb in a
+// line's fmt.Print*-style string literal, if it has one.
+func literalPrefix(text string) (string, bool) {
+	m := quotedLiteralRe.FindStringSubmatch(text)
+	if m == nil {
+		return "", false
+	}
+	prefix := m[1]
+	if i := strings.IndexByte(prefix, '%'); i >= 0 {
+		prefix = prefix[:i]
+	}
+	if prefix == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+func matchLine(matchers []literalMatcher, text string) ([]int, bool) {
+	for _, m := range matchers {
+		if strings.HasPrefix(text, m.prefix) {
+			return m.lines, true
+		}
+	}
+	return nil, false
+}
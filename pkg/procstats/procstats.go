@@ -0,0 +1,50 @@
+// Package procstats samples a process's resource usage and correlates
+// each sample with the most recent instrumented source line the
+// process hit, so a user can see e.g. whether a counter increment on
+// an annotated line tracks with heap growth.
+//
+// CPUPercent, RSSBytes and OpenFDs come from the OS on every
+// supported platform. Goroutine count and heap-in-use require reading
+// the target's own runtime state, which the OS can't see; Monitor
+// only fills them in when given DebugAddr, pointing at a
+// net/http/pprof endpoint the target opts into by blank-importing
+// net/http/pprof and serving it. Without DebugAddr they stay at zero.
+package procstats
+
+// Sample is one resource reading for a process.
+type Sample struct {
+	Time       int64   `json:"time"`
+	CPUPercent float64 `json:"cpu_percent"`
+	RSSBytes   uint64  `json:"rss_bytes"`
+	Goroutines int     `json:"goroutines,omitempty"`
+	HeapInUse  uint64  `json:"heap_in_use_bytes,omitempty"`
+	OpenFDs    int     `json:"open_fds"`
+}
+
+// Sampler reads one Sample for pid. Implementations are stateful:
+// CPUPercent is a delta since the previous call for the same pid.
+type Sampler interface {
+	Sample(pid int) (Sample, error)
+}
+
+// LineHit is one instrumented source line observed during a run.
+type LineHit struct {
+	Time int64  `json:"time"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// Event is one entry of the merged timeline: either a resource
+// Sample or a LineHit, never both.
+type Event struct {
+	Time     int64    `json:"time"`
+	Resource *Sample  `json:"resource,omitempty"`
+	Line     *LineHit `json:"line,omitempty"`
+}
+
+// Summary is reported once a monitored run exits.
+type Summary struct {
+	PeakRSSBytes   uint64
+	MeanCPUPercent float64
+	LineHits       map[int]int // line number -> times observed
+}
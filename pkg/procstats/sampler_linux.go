@@ -0,0 +1,97 @@
+//go:build linux
+
+package procstats
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const clockTicksPerSec = 100 // USER_HZ on every mainstream distro; see pkg/trace
+
+// linuxSampler reads /proc/<pid>/{stat,status,fd} — no cgo required.
+type linuxSampler struct {
+	prevTicks map[int]uint64
+	prevAt    map[int]time.Time
+}
+
+// NewSampler returns the Linux Sampler.
+func NewSampler() Sampler {
+	return &linuxSampler{prevTicks: map[int]uint64{}, prevAt: map[int]time.Time{}}
+}
+
+func (s *linuxSampler) Sample(pid int) (Sample, error) {
+	ticks, rss, err := readStat(pid)
+	if err != nil {
+		return Sample{}, err
+	}
+
+	now := time.Now()
+	var cpuPct float64
+	if prev, ok := s.prevTicks[pid]; ok {
+		elapsed := now.Sub(s.prevAt[pid]).Seconds()
+		if elapsed > 0 {
+			cpuPct = (float64(ticks-prev) / clockTicksPerSec) / elapsed * 100
+		}
+	}
+	s.prevTicks[pid] = ticks
+	s.prevAt[pid] = now
+
+	fds, _ := countFDs(pid)
+
+	return Sample{
+		Time:       now.UnixMilli(),
+		CPUPercent: cpuPct,
+		RSSBytes:   rss,
+		OpenFDs:    fds,
+	}, nil
+}
+
+// readStat returns cumulative utime+stime ticks (from /proc/pid/stat)
+// and RSS in bytes (from /proc/pid/status).
+func readStat(pid int) (ticks uint64, rssBytes uint64, err error) {
+	raw, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+	s := string(raw)
+	close := strings.LastIndexByte(s, ')')
+	if close < 0 {
+		return 0, 0, fmt.Errorf("procstats: malformed /proc/%d/stat", pid)
+	}
+	fields := strings.Fields(s[close+2:])
+	if len(fields) < 14 {
+		return 0, 0, fmt.Errorf("procstats: malformed /proc/%d/stat", pid)
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	ticks = utime + stime
+
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return ticks, 0, nil // CPU is still useful without RSS
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		if !strings.HasPrefix(line, "VmRSS:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			kb, _ := strconv.ParseUint(fields[1], 10, 64)
+			rssBytes = kb * 1024
+		}
+		break
+	}
+	return ticks, rssBytes, nil
+}
+
+func countFDs(pid int) (int, error) {
+	entries, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", pid))
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,332 @@
+package replay
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+
+	"github.com/trycog/cog-cli/pkg/lineinfo"
+)
+
+// Recorder interprets one source file's int-valued functions well
+// enough to journal their execution: entry/exit, argument values, and
+// every assignment on an annotated line.
+type Recorder struct {
+	fset    *token.FileSet
+	lines   *lineinfo.File
+	funcs   map[string]*ast.FuncDecl
+	journal *Journal
+}
+
+// NewRecorder parses path and indexes its top-level functions.
+func NewRecorder(path string) (*Recorder, error) {
+	fset := token.NewFileSet()
+	src, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	li, err := lineinfo.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := map[string]*ast.FuncDecl{}
+	for _, d := range src.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil {
+			funcs[fn.Name.Name] = fn
+		}
+	}
+	return &Recorder{fset: fset, lines: li, funcs: funcs}, nil
+}
+
+// Record interprets entry(args...) and returns the completed journal
+// plus the call's result.
+func (r *Recorder) Record(entry string, args ...int) (*Journal, int, error) {
+	r.journal = NewJournal()
+	result, err := r.call(entry, args)
+	return r.journal, result, err
+}
+
+type frame struct {
+	locals map[string]int
+}
+
+func (r *Recorder) call(name string, args []int) (int, error) {
+	fn, ok := r.funcs[name]
+	if !ok {
+		return 0, fmt.Errorf("replay: undefined function %q", name)
+	}
+
+	locals := map[string]int{}
+	argVals := map[string]string{}
+	i := 0
+	for _, field := range fn.Type.Params.List {
+		for _, n := range field.Names {
+			if i >= len(args) {
+				break
+			}
+			locals[n.Name] = args[i]
+			argVals[n.Name] = strconv.Itoa(args[i])
+			i++
+		}
+	}
+	r.journal.Append(Event{Kind: EventCall, Func: name, Line: r.fset.Position(fn.Pos()).Line, Args: argVals})
+
+	f := &frame{locals: locals}
+	result, returned, err := r.execStmts(fn.Body.List, f)
+	if err != nil {
+		return 0, err
+	}
+	if !returned {
+		result = 0
+	}
+	r.journal.Append(Event{Kind: EventReturn, Func: name, Line: r.fset.Position(fn.Body.Rbrace).Line, Result: strconv.Itoa(result)})
+	return result, nil
+}
+
+// execStmts runs a statement list, stopping at the first return.
+func (r *Recorder) execStmts(stmts []ast.Stmt, f *frame) (result int, returned bool, err error) {
+	for _, stmt := range stmts {
+		result, returned, err = r.execStmt(stmt, f)
+		if err != nil || returned {
+			return result, returned, err
+		}
+	}
+	return 0, false, nil
+}
+
+func (r *Recorder) execStmt(stmt ast.Stmt, f *frame) (int, bool, error) {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		return 0, false, r.execAssign(s, f)
+
+	case *ast.IncDecStmt:
+		return 0, false, r.execIncDec(s, f)
+
+	case *ast.ReturnStmt:
+		if len(s.Results) != 1 {
+			return 0, true, nil
+		}
+		v, err := r.eval(s.Results[0], f)
+		return v, true, err
+
+	case *ast.IfStmt:
+		cond, err := r.evalCond(s.Cond, f)
+		if err != nil {
+			return 0, false, err
+		}
+		if cond {
+			return r.execStmts(s.Body.List, f)
+		}
+		if s.Else != nil {
+			return r.execStmt(s.Else, f)
+		}
+		return 0, false, nil
+
+	case *ast.BlockStmt:
+		return r.execStmts(s.List, f)
+
+	case *ast.ForStmt:
+		return r.execFor(s, f)
+
+	case *ast.ExprStmt:
+		_, err := r.eval(s.X, f)
+		return 0, false, err
+
+	default:
+		return 0, false, fmt.Errorf("replay: unsupported statement %T", stmt)
+	}
+}
+
+func (r *Recorder) execFor(s *ast.ForStmt, f *frame) (int, bool, error) {
+	if s.Init != nil {
+		if err := r.execAssign(s.Init.(*ast.AssignStmt), f); err != nil {
+			return 0, false, err
+		}
+	}
+	for {
+		if s.Cond != nil {
+			cond, err := r.evalCond(s.Cond, f)
+			if err != nil {
+				return 0, false, err
+			}
+			if !cond {
+				return 0, false, nil
+			}
+		}
+
+		result, returned, err := r.execStmts(s.Body.List, f)
+		if err != nil || returned {
+			return result, returned, err
+		}
+
+		if s.Post != nil {
+			if _, _, err := r.execStmt(s.Post, f); err != nil {
+				return 0, false, err
+			}
+		}
+	}
+}
+
+// execAssign evaluates an AssignStmt and, if it lands on an annotated
+// line, journals the old/new value of the assigned variable alongside
+// a snapshot of the frame's other locals.
+func (r *Recorder) execAssign(s *ast.AssignStmt, f *frame) error {
+	if len(s.Lhs) != 1 || len(s.Rhs) != 1 {
+		return fmt.Errorf("replay: unsupported multi-assign at line %d", r.fset.Position(s.Pos()).Line)
+	}
+	ident, ok := s.Lhs[0].(*ast.Ident)
+	if !ok || ident.Name == "_" {
+		return nil
+	}
+
+	old, hadOld := f.locals[ident.Name]
+	oldStr := "<undefined>"
+	if hadOld {
+		oldStr = strconv.Itoa(old)
+	}
+
+	v, err := r.eval(s.Rhs[0], f)
+	if err != nil {
+		return err
+	}
+	f.locals[ident.Name] = v
+
+	line := r.fset.Position(s.Pos()).Line
+	if !r.lines.Annotated(line) {
+		return nil
+	}
+
+	r.journal.Append(Event{
+		Kind:   EventAssign,
+		Line:   line,
+		Var:    ident.Name,
+		Old:    oldStr,
+		New:    strconv.Itoa(v),
+		Locals: otherLocals(f.locals, ident.Name),
+	})
+	return nil
+}
+
+func (r *Recorder) execIncDec(s *ast.IncDecStmt, f *frame) error {
+	ident, ok := s.X.(*ast.Ident)
+	if !ok {
+		return fmt.Errorf("replay: unsupported inc/dec target at line %d", r.fset.Position(s.Pos()).Line)
+	}
+	if s.Tok == token.INC {
+		f.locals[ident.Name]++
+	} else {
+		f.locals[ident.Name]--
+	}
+	return nil
+}
+
+// eval computes an int-valued expression, calling into r.call for
+// nested function calls (including recursion).
+func (r *Recorder) eval(expr ast.Expr, f *frame) (int, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return strconv.Atoi(e.Value)
+
+	case *ast.Ident:
+		v, ok := f.locals[e.Name]
+		if !ok {
+			return 0, fmt.Errorf("replay: undefined variable %q", e.Name)
+		}
+		return v, nil
+
+	case *ast.ParenExpr:
+		return r.eval(e.X, f)
+
+	case *ast.BinaryExpr:
+		lhs, err := r.eval(e.X, f)
+		if err != nil {
+			return 0, err
+		}
+		rhs, err := r.eval(e.Y, f)
+		if err != nil {
+			return 0, err
+		}
+		switch e.Op {
+		case token.ADD:
+			return lhs + rhs, nil
+		case token.SUB:
+			return lhs - rhs, nil
+		case token.MUL:
+			return lhs * rhs, nil
+		case token.QUO:
+			return lhs / rhs, nil
+		default:
+			return 0, fmt.Errorf("replay: unsupported operator %s", e.Op)
+		}
+
+	case *ast.CallExpr:
+		ident, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return 0, fmt.Errorf("replay: unsupported call target")
+		}
+		args := make([]int, len(e.Args))
+		for i, a := range e.Args {
+			v, err := r.eval(a, f)
+			if err != nil {
+				return 0, err
+			}
+			args[i] = v
+		}
+		return r.call(ident.Name, args)
+
+	default:
+		return 0, fmt.Errorf("replay: unsupported expression %T", expr)
+	}
+}
+
+// evalCond evaluates a boolean comparison.
+func (r *Recorder) evalCond(expr ast.Expr, f *frame) (bool, error) {
+	e, ok := expr.(*ast.BinaryExpr)
+	if !ok {
+		return false, fmt.Errorf("replay: unsupported condition %T", expr)
+	}
+	lhs, err := r.eval(e.X, f)
+	if err != nil {
+		return false, err
+	}
+	rhs, err := r.eval(e.Y, f)
+	if err != nil {
+		return false, err
+	}
+	switch e.Op {
+	case token.LSS:
+		return lhs < rhs, nil
+	case token.LEQ:
+		return lhs <= rhs, nil
+	case token.GTR:
+		return lhs > rhs, nil
+	case token.GEQ:
+		return lhs >= rhs, nil
+	case token.EQL:
+		return lhs == rhs, nil
+	case token.NEQ:
+		return lhs != rhs, nil
+	default:
+		return false, fmt.Errorf("replay: unsupported comparison %s", e.Op)
+	}
+}
+
+func otherLocals(locals map[string]int, except string) map[string]string {
+	out := map[string]string{}
+	names := make([]string, 0, len(locals))
+	for name := range locals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == except {
+			continue
+		}
+		out[name] = strconv.Itoa(locals[name])
+	}
+	return out
+}
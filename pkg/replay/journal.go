@@ -0,0 +1,128 @@
+// Package replay records a deterministic execution journal for an
+// annotated Go fixture and lets callers step through it forwards or
+// backwards.
+//
+// There is no process-level recorder here (no ptrace, no compiler
+// hook): Recorder is a small tree-walking interpreter over the subset
+// of Go the cog fixtures use (int arithmetic, if/for, calls,
+// recursion). That keeps recording deterministic and bounds overhead
+// to exactly the annotated "// line N" lines, at the cost of only
+// supporting that subset — enough to record compute/loopSum/factorial
+// today, with the same Journal format ready for a real in-process
+// recorder later.
+package replay
+
+// EventKind distinguishes the three things a journal records.
+type EventKind int
+
+const (
+	EventCall EventKind = iota
+	EventReturn
+	EventAssign
+)
+
+// Event is one journaled step.
+type Event struct {
+	Step int
+	Kind EventKind
+	Func string
+	Line int
+
+	// EventAssign
+	Var    string
+	Old    string
+	New    string
+	Locals map[string]string // other in-scope locals, for context
+
+	// EventCall
+	Args map[string]string
+
+	// EventReturn
+	Result string
+}
+
+// chunkSize bounds how many events live in one Journal chunk, so a
+// step can be addressed directly by step/chunkSize instead of
+// rescanning the whole journal for O(1) lookup.
+const chunkSize = 64
+
+type chunk struct {
+	events []Event
+}
+
+// Journal is an append-only, randomly-steppable event log.
+type Journal struct {
+	chunks    []*chunk
+	funcCalls map[string][]int // function name -> steps where it was entered
+	len       int
+}
+
+// NewJournal returns an empty Journal.
+func NewJournal() *Journal {
+	return &Journal{funcCalls: map[string][]int{}}
+}
+
+// Append adds e to the end of the journal, stamping its Step.
+func (j *Journal) Append(e Event) Event {
+	e.Step = j.len
+
+	last := j.lastChunk()
+	if last == nil || len(last.events) >= chunkSize {
+		last = &chunk{}
+		j.chunks = append(j.chunks, last)
+	}
+	last.events = append(last.events, e)
+	j.len++
+
+	if e.Kind == EventCall {
+		j.funcCalls[e.Func] = append(j.funcCalls[e.Func], e.Step)
+	}
+	return e
+}
+
+func (j *Journal) lastChunk() *chunk {
+	if len(j.chunks) == 0 {
+		return nil
+	}
+	return j.chunks[len(j.chunks)-1]
+}
+
+// Len returns the number of recorded events.
+func (j *Journal) Len() int { return j.len }
+
+// At returns the event recorded at step, in O(1): the chunk is
+// addressed directly by step/chunkSize, then indexed within it.
+func (j *Journal) At(step int) (Event, bool) {
+	if step < 0 || step >= j.len {
+		return Event{}, false
+	}
+	c := j.chunks[step/chunkSize]
+	return c.events[step%chunkSize], true
+}
+
+// CallSteps returns every step at which fn was entered, in order.
+func (j *Journal) CallSteps(fn string) []int {
+	return j.funcCalls[fn]
+}
+
+// Events returns every recorded event in order, for serialization —
+// Journal's own fields are unexported so gob cannot encode it
+// directly.
+func (j *Journal) Events() []Event {
+	out := make([]Event, 0, j.len)
+	for i := 0; i < j.len; i++ {
+		e, _ := j.At(i)
+		out = append(out, e)
+	}
+	return out
+}
+
+// FromEvents rebuilds a Journal from a previously serialized event
+// slice, e.g. one loaded from disk.
+func FromEvents(events []Event) *Journal {
+	j := NewJournal()
+	for _, e := range events {
+		j.Append(e)
+	}
+	return j
+}
@@ -0,0 +1,75 @@
+package replay
+
+// Player steps through a recorded Journal forwards or backwards.
+type Player struct {
+	j   *Journal
+	pos int // next step Step() would return; -1 before the first event
+}
+
+// NewPlayer returns a Player positioned before the journal's first
+// event.
+func NewPlayer(j *Journal) *Player {
+	return &Player{j: j, pos: -1}
+}
+
+// Step advances one event forward.
+func (p *Player) Step() (Event, bool) {
+	if p.pos+1 >= p.j.Len() {
+		return Event{}, false
+	}
+	p.pos++
+	return p.j.At(p.pos)
+}
+
+// ReverseStep moves one event backward.
+func (p *Player) ReverseStep() (Event, bool) {
+	if p.pos < 0 {
+		return Event{}, false
+	}
+	e, ok := p.j.At(p.pos)
+	p.pos--
+	return e, ok
+}
+
+// ReverseContinueUntil steps backward until pred matches an event (or
+// the start of the journal is reached), returning the matching event.
+func (p *Player) ReverseContinueUntil(pred func(Event) bool) (Event, bool) {
+	for {
+		e, ok := p.ReverseStep()
+		if !ok {
+			return Event{}, false
+		}
+		if pred(e) {
+			return e, true
+		}
+	}
+}
+
+// FramesAt reconstructs the call stack at step and returns the
+// EventCall for every active frame whose function is fn, outermost
+// first — e.g. every still-open recursive call to factorial.
+func (p *Player) FramesAt(step int, fn string) []Event {
+	var stack []Event
+	for s := 0; s <= step; s++ {
+		e, ok := p.j.At(s)
+		if !ok {
+			break
+		}
+		switch e.Kind {
+		case EventCall:
+			stack = append(stack, e)
+		case EventReturn:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	var frames []Event
+	for _, e := range stack {
+		if e.Func == fn {
+			frames = append(frames, e)
+		}
+	}
+	return frames
+}
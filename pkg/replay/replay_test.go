@@ -0,0 +1,60 @@
+package replay
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// TestLoopSumReverseTrace records loopSum(5) from the compute fixture,
+// reverse-steps the resulting journal back to the start, and asserts
+// the reconstructed forward sequence of total is 0, 1, 3, 6, 10, 15.
+func TestLoopSumReverseTrace(t *testing.T) {
+	fixture := filepath.Join("..", "..", "prompts", "fixtures", "go", "compute", "debug_compute.go")
+
+	rec, err := NewRecorder(fixture)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	journal, result, err := rec.Record("loopSum", 5)
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if result != 15 {
+		t.Fatalf("loopSum(5) = %d, want 15", result)
+	}
+
+	p := NewPlayer(journal)
+	for {
+		if _, ok := p.Step(); !ok {
+			break
+		}
+	}
+
+	var reversed []int
+	for {
+		e, ok := p.ReverseStep()
+		if !ok {
+			break
+		}
+		if e.Kind != EventAssign || e.Var != "total" {
+			continue
+		}
+		v, err := strconv.Atoi(e.New)
+		if err != nil {
+			t.Fatalf("non-numeric total %q: %v", e.New, err)
+		}
+		reversed = append(reversed, v)
+	}
+
+	got := make([]int, len(reversed))
+	for i, v := range reversed {
+		got[len(reversed)-1-i] = v
+	}
+
+	want := []int{0, 1, 3, 6, 10, 15}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("reverse trace reconstructed total = %v, want %v", got, want)
+	}
+}
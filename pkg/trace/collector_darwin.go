@@ -0,0 +1,49 @@
+//go:build darwin
+
+package trace
+
+/*
+#include <libproc.h>
+#include <string.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// readCPUNanos returns cumulative scheduled CPU time, in nanoseconds,
+// for the process (key -1) and, when perP is set, for each of its
+// threads (keyed by thread id), via libproc — the same source
+// pkg/procstats uses for per-process accounting.
+func readCPUNanos(pid int, perP bool) (map[int]uint64, error) {
+	var info C.struct_proc_taskinfo
+	n := C.proc_pidinfo(C.int(pid), C.PROC_PIDTASKINFO, 0, unsafe.Pointer(&info), C.int(unsafe.Sizeof(info)))
+	if int(n) != int(unsafe.Sizeof(info)) {
+		return nil, fmt.Errorf("trace: proc_pidinfo(PROC_PIDTASKINFO, %d) failed", pid)
+	}
+
+	nanos := map[int]uint64{-1: uint64(info.pti_total_user) + uint64(info.pti_total_system)}
+	if !perP {
+		return nanos, nil
+	}
+
+	const maxThreads = 4096
+	var tids [maxThreads]C.uint64_t
+	sz := C.proc_pidinfo(C.int(pid), C.PROC_PIDLISTTHREADS, 0, unsafe.Pointer(&tids[0]), C.int(unsafe.Sizeof(tids)))
+	if sz <= 0 {
+		return nanos, nil // per-thread breakdown is best-effort
+	}
+	count := int(sz) / int(unsafe.Sizeof(tids[0]))
+
+	for i := 0; i < count && i < maxThreads; i++ {
+		var tinfo C.struct_proc_threadinfo
+		n := C.proc_pidinfo(C.int(pid), C.PROC_PIDTHREADINFO, C.uint64_t(tids[i]), unsafe.Pointer(&tinfo), C.int(unsafe.Sizeof(tinfo)))
+		if int(n) != int(unsafe.Sizeof(tinfo)) {
+			continue
+		}
+		nanos[int(tids[i])] = uint64(tinfo.pth_user_time) + uint64(tinfo.pth_system_time)
+	}
+	return nanos, nil
+}
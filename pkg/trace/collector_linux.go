@@ -0,0 +1,79 @@
+//go:build linux
+
+package trace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// clockTicksPerSec is the kernel's USER_HZ. 100 is the value on every
+// mainstream Linux distribution; reading the real value needs cgo
+// (sysconf), which this package avoids.
+const clockTicksPerSec = 100
+
+// readCPUNanos returns cumulative scheduled CPU time, in nanoseconds,
+// for the process (key -1) and, when perP is set, for each of its
+// threads (keyed by tid).
+func readCPUNanos(pid int, perP bool) (map[int]uint64, error) {
+	nanos := map[int]uint64{}
+
+	self, err := statNanos(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+	nanos[-1] = self
+
+	if !perP {
+		return nanos, nil
+	}
+
+	entries, err := os.ReadDir(fmt.Sprintf("/proc/%d/task", pid))
+	if err != nil {
+		return nanos, nil // thread breakdown is best-effort
+	}
+	for _, e := range entries {
+		tid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		t, err := statNanos(filepath.Join("/proc", strconv.Itoa(pid), "task", e.Name(), "stat"))
+		if err == nil {
+			nanos[tid] = t
+		}
+	}
+	return nanos, nil
+}
+
+// statNanos reads utime+stime (fields 14 and 15) from a /proc/*/stat
+// file and converts them from clock ticks to nanoseconds. The comm
+// field is parenthesized and may itself contain spaces, so fields are
+// counted from the last ')'.
+func statNanos(path string) (uint64, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	s := string(raw)
+	close := strings.LastIndexByte(s, ')')
+	if close < 0 || close+2 >= len(s) {
+		return 0, fmt.Errorf("trace: malformed %s", path)
+	}
+	fields := strings.Fields(s[close+2:])
+	if len(fields) < 14 {
+		return 0, fmt.Errorf("trace: malformed %s", path)
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	ticks := utime + stime
+	return ticks * (1e9 / clockTicksPerSec), nil
+}
@@ -0,0 +1,103 @@
+//go:build windows
+
+package trace
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                = syscall.NewLazyDLL("kernel32.dll")
+	procOpenProcess         = kernel32.NewProc("OpenProcess")
+	procOpenThread          = kernel32.NewProc("OpenThread")
+	procCloseHandle         = kernel32.NewProc("CloseHandle")
+	procGetProcessTimes     = kernel32.NewProc("GetProcessTimes")
+	procGetThreadTimes      = kernel32.NewProc("GetThreadTimes")
+	procCreateToolhelp32Snp = kernel32.NewProc("CreateToolhelp32Snapshot")
+	procThread32First       = kernel32.NewProc("Thread32First")
+	procThread32Next        = kernel32.NewProc("Thread32Next")
+)
+
+const (
+	th32csSnapThread        = 0x00000004
+	processQueryLimitedInfo = 0x1000
+	threadQueryLimitedInfo  = 0x0800
+	invalidHandle           = ^uintptr(0)
+)
+
+// threadEntry32 mirrors THREADENTRY32.
+type threadEntry32 struct {
+	Size           uint32
+	UsageCount     uint32
+	ThreadID       uint32
+	OwnerProcessID uint32
+	BasePri        int32
+	DeltaPri       int32
+	Flags          uint32
+}
+
+// readCPUNanos returns cumulative scheduled CPU time, in nanoseconds,
+// for the process (key -1) and, when perP is set, for each of its
+// threads (keyed by thread id), via kernel32 — no cgo.
+func readCPUNanos(pid int, perP bool) (map[int]uint64, error) {
+	h, _, _ := procOpenProcess.Call(uintptr(processQueryLimitedInfo), 0, uintptr(pid))
+	if h == 0 {
+		return nil, fmt.Errorf("trace: OpenProcess(%d) failed", pid)
+	}
+	defer procCloseHandle.Call(h)
+
+	var creation, exit, kernelT, userT syscall.Filetime
+	ret, _, _ := procGetProcessTimes.Call(h,
+		uintptr(unsafe.Pointer(&creation)), uintptr(unsafe.Pointer(&exit)),
+		uintptr(unsafe.Pointer(&kernelT)), uintptr(unsafe.Pointer(&userT)))
+	if ret == 0 {
+		return nil, fmt.Errorf("trace: GetProcessTimes(%d) failed", pid)
+	}
+
+	nanos := map[int]uint64{-1: (filetimeTo100ns(kernelT) + filetimeTo100ns(userT)) * 100}
+	if !perP {
+		return nanos, nil
+	}
+
+	snap, _, _ := procCreateToolhelp32Snp.Call(uintptr(th32csSnapThread), 0)
+	if snap == invalidHandle || snap == 0 {
+		return nanos, nil // per-thread breakdown is best-effort
+	}
+	defer procCloseHandle.Call(snap)
+
+	var te threadEntry32
+	te.Size = uint32(unsafe.Sizeof(te))
+	ok, _, _ := procThread32First.Call(snap, uintptr(unsafe.Pointer(&te)))
+	for ok != 0 {
+		if int(te.OwnerProcessID) == pid {
+			if n, err := threadNanos(te.ThreadID); err == nil {
+				nanos[int(te.ThreadID)] = n
+			}
+		}
+		ok, _, _ = procThread32Next.Call(snap, uintptr(unsafe.Pointer(&te)))
+	}
+	return nanos, nil
+}
+
+func threadNanos(tid uint32) (uint64, error) {
+	h, _, _ := procOpenThread.Call(uintptr(threadQueryLimitedInfo), 0, uintptr(tid))
+	if h == 0 {
+		return 0, fmt.Errorf("trace: OpenThread(%d) failed", tid)
+	}
+	defer procCloseHandle.Call(h)
+
+	var creation, exit, kernelT, userT syscall.Filetime
+	ret, _, _ := procGetThreadTimes.Call(h,
+		uintptr(unsafe.Pointer(&creation)), uintptr(unsafe.Pointer(&exit)),
+		uintptr(unsafe.Pointer(&kernelT)), uintptr(unsafe.Pointer(&userT)))
+	if ret == 0 {
+		return 0, fmt.Errorf("trace: GetThreadTimes(%d) failed", tid)
+	}
+	return (filetimeTo100ns(kernelT) + filetimeTo100ns(userT)) * 100, nil
+}
+
+func filetimeTo100ns(ft syscall.Filetime) uint64 {
+	return uint64(ft.HighDateTime)<<32 | uint64(ft.LowDateTime)
+}
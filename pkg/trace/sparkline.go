@@ -0,0 +1,21 @@
+package trace
+
+var sparkBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// Sparkline renders utilization values in [0,1] as a single line of
+// Unicode block characters, suitable for printing to a terminal on
+// each sample.
+func Sparkline(values []float64) string {
+	out := make([]rune, len(values))
+	for i, v := range values {
+		if v < 0 {
+			v = 0
+		}
+		if v > 1 {
+			v = 1
+		}
+		idx := int(v * float64(len(sparkBlocks)-1))
+		out[i] = sparkBlocks[idx]
+	}
+	return string(out)
+}
@@ -0,0 +1,119 @@
+// Package trace attaches to a running process and computes a Mutator
+// Utilization Function (MUF): a time-ordered series of mean mutator
+// utilization in [0,1] over fixed-width windows, the same shape the Go
+// GC trace viewer plots.
+//
+// A trace-exact MUF requires decoding the v2 runtime/trace wire format
+// and bucketing STW/mark-assist/sweep spans, which needs either an
+// in-process trace.Start call or a full trace-format reader — neither
+// of which this package has yet. Until cog grows its own reader,
+// Collector approximates mutator utilization from OS-level CPU-time
+// sampling: the fraction of each window the target spent scheduled on
+// CPU. That data source can't distinguish GC phases, so there is
+// deliberately no STW/mark-assist/sweep include/exclude knob here —
+// add one once a real trace reader can back it.
+package trace
+
+import (
+	"fmt"
+	"time"
+)
+
+// Sample is one point of a utilization series.
+type Sample struct {
+	Time int64   `json:"time"`
+	Util float64 `json:"util"`
+}
+
+// Series is one mutator utilization time series: the process-wide
+// aggregate when Thread is -1, or one per OS thread when PerThread is
+// set. This is OS thread CPU time, not GOMAXPROCS logical Ps — M:N
+// goroutine scheduling means OS thread count doesn't track them.
+type Series struct {
+	Thread  int      `json:"thread"`
+	Samples []Sample `json:"samples"`
+}
+
+// Options configures what Collector.Run measures and emits.
+type Options struct {
+	Window time.Duration
+
+	// PerThread emits one series per OS thread instead of a single
+	// process-wide aggregate. It is not one series per logical
+	// processor (GOMAXPROCS): OS thread count doesn't track that under
+	// Go's M:N scheduler.
+	PerThread bool
+}
+
+// DefaultOptions returns the single-series Options most callers want.
+func DefaultOptions() Options {
+	return Options{Window: 100 * time.Millisecond}
+}
+
+// Collector samples a running process's scheduled CPU time on a fixed
+// cadence to approximate its mutator utilization. Each platform
+// supplies readCPUNanos; the sampling loop itself is shared.
+type Collector struct {
+	PID     int
+	Options Options
+}
+
+// NewCollector returns a Collector attached to pid.
+func NewCollector(pid int, opts Options) *Collector {
+	return &Collector{PID: pid, Options: opts}
+}
+
+// Run samples until duration elapses, invoking onSample once per
+// window with the utilization of each monitored series (key -1 is the
+// process-wide aggregate; PerThread adds one key per OS thread id).
+func (c *Collector) Run(duration time.Duration, onSample func(t int64, util map[int]float64)) error {
+	window := c.Options.Window
+	if window <= 0 {
+		window = DefaultOptions().Window
+	}
+
+	prevNanos, err := readCPUNanos(c.PID, c.Options.PerThread)
+	if err != nil {
+		return fmt.Errorf("trace: attach pid %d: %w", c.PID, err)
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+
+	for elapsed := time.Duration(0); elapsed < duration; {
+		<-ticker.C
+		elapsed = time.Since(start)
+
+		curNanos, err := readCPUNanos(c.PID, c.Options.PerThread)
+		if err != nil {
+			return fmt.Errorf("trace: pid %d exited during sampling: %w", c.PID, err)
+		}
+
+		util := map[int]float64{}
+		util[-1] = utilization(prevNanos[-1], curNanos[-1], window)
+		if c.Options.PerThread {
+			for key, cur := range curNanos {
+				if key == -1 {
+					continue
+				}
+				util[key] = utilization(prevNanos[key], cur, window)
+			}
+		}
+
+		onSample(elapsed.Milliseconds(), util)
+		prevNanos = curNanos
+	}
+	return nil
+}
+
+func utilization(prevNanos, curNanos uint64, window time.Duration) float64 {
+	u := float64(curNanos-prevNanos) / 1e9 / window.Seconds()
+	if u < 0 {
+		return 0
+	}
+	if u > 1 {
+		return 1
+	}
+	return u
+}